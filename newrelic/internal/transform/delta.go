@@ -0,0 +1,52 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"sync"
+	"time"
+)
+
+// DeltaConverter converts the cumulative values reported by OpenTelemetry's
+// default aggregators (Sum, Histogram, and cumulative SumObservers) into the
+// delta values the New Relic backend expects. It tracks the previous value
+// and collection time per (descriptor, attribute-set) key across collection
+// cycles.
+//
+// A DeltaConverter is safe for concurrent use.
+type DeltaConverter struct {
+	mu    sync.Mutex
+	state map[string]deltaEntry
+}
+
+type deltaEntry struct {
+	value float64
+	time  time.Time
+}
+
+// NewDeltaConverter returns an empty DeltaConverter.
+func NewDeltaConverter() *DeltaConverter {
+	return &DeltaConverter{state: make(map[string]deltaEntry)}
+}
+
+// Convert returns the delta of value since the previous call for key, and
+// the interval elapsed since that call. The first observation for a key,
+// and any observation lower than the previous one for that key (indicating
+// the underlying counter was reset), re-seed the tracked state and return
+// value itself as the delta with a zero interval.
+func (c *DeltaConverter) Convert(key string, value float64, now time.Time) (delta float64, interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, ok := c.state[key]
+	c.state[key] = deltaEntry{value: value, time: now}
+	if !ok || value < prev.value {
+		return value, 0
+	}
+	return value - prev.value, now.Sub(prev.time)
+}
+
+// defaultDeltaConverter is the DeltaConverter Record consults when no
+// alternative is supplied via an Option.
+var defaultDeltaConverter = NewDeltaConverter()
@@ -0,0 +1,70 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeltaConverterFirstObservation(t *testing.T) {
+	d := NewDeltaConverter()
+	now := time.Now()
+
+	value, interval := d.Convert("k", 10, now)
+	if value != 10 {
+		t.Fatalf("expected the first observation's value to pass through as the delta, got %v", value)
+	}
+	if interval != 0 {
+		t.Fatalf("expected a zero interval for the first observation, got %v", interval)
+	}
+}
+
+func TestDeltaConverterSubsequentObservation(t *testing.T) {
+	d := NewDeltaConverter()
+	start := time.Now()
+
+	d.Convert("k", 10, start)
+	value, interval := d.Convert("k", 25, start.Add(5*time.Second))
+	if value != 15 {
+		t.Fatalf("expected delta 15 (25-10), got %v", value)
+	}
+	if interval != 5*time.Second {
+		t.Fatalf("expected a 5s interval, got %v", interval)
+	}
+}
+
+func TestDeltaConverterCounterReset(t *testing.T) {
+	d := NewDeltaConverter()
+	start := time.Now()
+
+	d.Convert("k", 100, start)
+	value, interval := d.Convert("k", 5, start.Add(time.Second))
+	if value != 5 {
+		t.Fatalf("expected a reset to re-seed and report the new value itself, got %v", value)
+	}
+	if interval != 0 {
+		t.Fatalf("expected a zero interval immediately after a reset, got %v", interval)
+	}
+
+	// The next call should resume computing deltas from the reseeded value.
+	value, interval = d.Convert("k", 8, start.Add(2*time.Second))
+	if value != 3 {
+		t.Fatalf("expected delta 3 (8-5) after the reset re-seeded state, got %v", value)
+	}
+	if interval != time.Second {
+		t.Fatalf("expected a 1s interval, got %v", interval)
+	}
+}
+
+func TestDeltaConverterIndependentKeys(t *testing.T) {
+	d := NewDeltaConverter()
+	now := time.Now()
+
+	d.Convert("a", 10, now)
+	value, _ := d.Convert("b", 1, now)
+	if value != 1 {
+		t.Fatalf("expected an unrelated key's first observation to be unaffected by key %q, got %v", "a", value)
+	}
+}
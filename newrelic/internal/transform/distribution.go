@@ -0,0 +1,51 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregation"
+)
+
+// distribution transforms a Distribution Aggregator aggregation into a New
+// Relic Summary carrying Min/Max/Sum/Count, plus one Gauge per requested
+// quantile named "<metric>.pNN" (e.g. "<metric>.p99" for the 0.99
+// quantile).
+func distribution(desc *metric.Descriptor, attrs map[string]interface{}, a aggregation.Distribution, quantiles []float64) ([]telemetry.Metric, error) {
+	min, max, sum, count, err := minMaxSumCountValues(a)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]telemetry.Metric, 0, 1+len(quantiles))
+	metrics = append(metrics, telemetry.Summary{
+		Name:       desc.Name(),
+		Attributes: attrs,
+		Count:      float64(count),
+		Sum:        sum.CoerceToFloat64(desc.NumberKind()),
+		Min:        min.CoerceToFloat64(desc.NumberKind()),
+		Max:        max.CoerceToFloat64(desc.NumberKind()),
+	})
+
+	for _, q := range quantiles {
+		v, err := a.Quantile(q)
+		if err != nil {
+			return nil, err
+		}
+		quantileAttrs := make(map[string]interface{}, len(attrs))
+		for k, v := range attrs {
+			quantileAttrs[k] = v
+		}
+		metrics = append(metrics, telemetry.Gauge{
+			Name:       fmt.Sprintf("%s.p%d", desc.Name(), int(q*100)),
+			Attributes: quantileAttrs,
+			Value:      v.CoerceToFloat64(desc.NumberKind()),
+		})
+	}
+
+	return metrics, nil
+}
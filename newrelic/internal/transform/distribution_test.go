@@ -0,0 +1,64 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/array"
+)
+
+func TestDistribution(t *testing.T) {
+	desc := metric.NewDescriptor("test.distribution", metric.ValueRecorderKind, metric.Int64NumberKind)
+	agg := &array.New(1)[0]
+	checkpoint := &array.New(1)[0]
+
+	for _, v := range []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10} {
+		if err := agg.Update(context.Background(), metric.NewInt64Number(v), &desc); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+	if err := agg.SynchronizedMove(checkpoint, &desc); err != nil {
+		t.Fatalf("SynchronizedMove: %v", err)
+	}
+
+	attrs := map[string]interface{}{"k": "v"}
+	metrics, err := distribution(&desc, attrs, checkpoint, []float64{0.5, 0.9})
+	if err != nil {
+		t.Fatalf("distribution: %v", err)
+	}
+
+	if want := 1 + 2; len(metrics) != want {
+		t.Fatalf("expected %d metrics, got %d", want, len(metrics))
+	}
+
+	summary, ok := metrics[0].(telemetry.Summary)
+	if !ok {
+		t.Fatalf("expected first metric to be a Summary, got %T", metrics[0])
+	}
+	if summary.Min != 1 || summary.Max != 10 || summary.Count != 10 || summary.Sum != 55 {
+		t.Fatalf("unexpected Summary: %+v", summary)
+	}
+
+	p50, ok := metrics[1].(telemetry.Gauge)
+	if !ok {
+		t.Fatalf("expected second metric to be a Gauge, got %T", metrics[1])
+	}
+	if p50.Name != "test.distribution.p50" {
+		t.Fatalf("expected p50 gauge name, got %q", p50.Name)
+	}
+
+	// Each quantile Gauge must own its attributes rather than aliasing the
+	// Summary's map: mutating one must not affect the others.
+	p50.Attributes["mutated"] = true
+	if _, ok := attrs["mutated"]; ok {
+		t.Fatalf("mutating a quantile Gauge's attributes leaked into the shared attrs map")
+	}
+	if _, ok := summary.Attributes["mutated"]; ok {
+		t.Fatalf("mutating a quantile Gauge's attributes leaked into the Summary's attributes")
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/label"
+)
+
+// testLabelSet returns a small, fixed label.Set shared by the transform
+// tests that only care about having a valid *label.Set to pass around.
+func testLabelSet() *label.Set {
+	set := label.NewSet(kv.String("label", "value"))
+	return &set
+}
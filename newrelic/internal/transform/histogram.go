@@ -0,0 +1,74 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"go.opentelemetry.io/otel/api/label"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregation"
+)
+
+// bucketLabelKey is the attribute New Relic convention uses to mark the
+// upper bound of a histogram bucket, mirroring Prometheus's "le" label.
+const bucketLabelKey = "le"
+
+// histogram transforms a Histogram Aggregator aggregation into a New Relic
+// Summary, along with one delta Count per bucket carrying an "le" attribute
+// for the bucket's upper boundary so the original distribution shape is
+// preserved. Bucket counts are cumulative, so each is run through delta,
+// keyed per-bucket off of the record's descriptor and labels.
+func histogram(desc *metric.Descriptor, labels *label.Set, attrs map[string]interface{}, a aggregation.Histogram, delta *DeltaConverter, now time.Time) ([]telemetry.Metric, error) {
+	sum, err := a.Sum()
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := a.Histogram()
+	if err != nil {
+		return nil, err
+	}
+
+	// aggregation.Histogram has no Count method; the total count is the sum
+	// of the bucket counts.
+	var count float64
+	for _, c := range buckets.Counts {
+		count += c
+	}
+
+	metrics := make([]telemetry.Metric, 0, 1+len(buckets.Counts))
+	metrics = append(metrics, telemetry.Summary{
+		Name:       desc.Name(),
+		Attributes: attrs,
+		Count:      count,
+		Sum:        sum.CoerceToFloat64(desc.NumberKind()),
+	})
+
+	baseKey := recordKey(desc, labels)
+	for i, c := range buckets.Counts {
+		bucketAttrs := make(map[string]interface{}, len(attrs)+1)
+		for k, v := range attrs {
+			bucketAttrs[k] = v
+		}
+		var bound string
+		if i < len(buckets.Boundaries) {
+			bound = strconv.FormatFloat(buckets.Boundaries[i], 'g', -1, 64)
+		} else {
+			bound = "+Inf"
+		}
+		bucketAttrs[bucketLabelKey] = bound
+
+		value, interval := delta.Convert(baseKey+"/"+bound, float64(c), now)
+		metrics = append(metrics, telemetry.Count{
+			Name:       desc.Name() + ".bucket",
+			Attributes: bucketAttrs,
+			Value:      value,
+			Interval:   interval,
+		})
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"go.opentelemetry.io/otel/api/metric"
+	histogramaggregator "go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+)
+
+func TestHistogram(t *testing.T) {
+	desc := metric.NewDescriptor("test.histogram", metric.ValueRecorderKind, metric.Int64NumberKind)
+	boundaries := []float64{10, 20}
+	agg := &histogramaggregator.New(1, &desc, boundaries)[0]
+
+	for _, v := range []int64{5, 15, 15, 25} {
+		if err := agg.Update(context.Background(), metric.NewInt64Number(v), &desc); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	now := time.Now()
+	delta := NewDeltaConverter()
+	labels := testLabelSet()
+	metrics, err := histogram(&desc, labels, map[string]interface{}{"k": "v"}, agg, delta, now)
+	if err != nil {
+		t.Fatalf("histogram: %v", err)
+	}
+
+	// One Summary plus one Count per bucket (len(boundaries)+1).
+	if want := 1 + len(boundaries) + 1; len(metrics) != want {
+		t.Fatalf("expected %d metrics, got %d", want, len(metrics))
+	}
+
+	summary, ok := metrics[0].(telemetry.Summary)
+	if !ok {
+		t.Fatalf("expected first metric to be a Summary, got %T", metrics[0])
+	}
+	if summary.Count != 4 {
+		t.Fatalf("expected count 4 (derived from bucket counts), got %v", summary.Count)
+	}
+	if summary.Sum != 60 {
+		t.Fatalf("expected sum 60, got %v", summary.Sum)
+	}
+
+	wantBucketCounts := []float64{1, 2, 1}
+	for i, want := range wantBucketCounts {
+		c, ok := metrics[i+1].(telemetry.Count)
+		if !ok {
+			t.Fatalf("bucket %d: expected a Count, got %T", i, metrics[i+1])
+		}
+		if c.Value != want {
+			t.Fatalf("bucket %d: expected delta value %v on first observation, got %v", i, want, c.Value)
+		}
+		if c.Attributes[bucketLabelKey] == "" {
+			t.Fatalf("bucket %d: expected %q attribute to be set", i, bucketLabelKey)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"go.opentelemetry.io/otel/api/label"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregation"
+)
+
+// lastValue transforms a LastValue Aggregator aggregation into a Gauge
+// Metric carrying the most recently observed value.
+//
+// Monotonic sums reported by SumObserver instruments are cumulative by
+// construction, so when desc describes one this instead returns a delta
+// Count Metric via delta, consistent with how Sum and Histogram are
+// converted.
+func lastValue(desc *metric.Descriptor, labels *label.Set, attrs map[string]interface{}, a aggregation.LastValue, delta *DeltaConverter, now time.Time) (telemetry.Metric, error) {
+	value, _, err := a.LastValue()
+	if err != nil {
+		return nil, err
+	}
+	v := value.CoerceToFloat64(desc.NumberKind())
+
+	if desc.MetricKind() == metric.SumObserverKind {
+		val, interval := delta.Convert(recordKey(desc, labels), v, now)
+		return telemetry.Count{
+			Name:       desc.Name(),
+			Attributes: attrs,
+			Value:      val,
+			Interval:   interval,
+		}, nil
+	}
+
+	return telemetry.Gauge{
+		Name:       desc.Name(),
+		Attributes: attrs,
+		Value:      v,
+	}, nil
+}
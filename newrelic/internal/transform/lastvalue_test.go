@@ -0,0 +1,71 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+)
+
+func newLastValueAgg(t *testing.T, desc *metric.Descriptor, v int64) *lastvalue.Aggregator {
+	t.Helper()
+	agg := &lastvalue.New(1)[0]
+	if err := agg.Update(context.Background(), metric.NewInt64Number(v), desc); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	return agg
+}
+
+func TestLastValueGauge(t *testing.T) {
+	desc := metric.NewDescriptor("test.observer", metric.ValueObserverKind, metric.Int64NumberKind)
+	agg := newLastValueAgg(t, &desc, 42)
+	labels := testLabelSet()
+
+	m, err := lastValue(&desc, labels, map[string]interface{}{"k": "v"}, agg, NewDeltaConverter(), time.Now())
+	if err != nil {
+		t.Fatalf("lastValue: %v", err)
+	}
+	g, ok := m.(telemetry.Gauge)
+	if !ok {
+		t.Fatalf("expected a Gauge for a ValueObserver, got %T", m)
+	}
+	if g.Value != 42 {
+		t.Fatalf("expected value 42, got %v", g.Value)
+	}
+}
+
+func TestLastValueSumObserverRoutesToDeltaCount(t *testing.T) {
+	desc := metric.NewDescriptor("test.sum_observer", metric.SumObserverKind, metric.Int64NumberKind)
+	labels := testLabelSet()
+	delta := NewDeltaConverter()
+	now := time.Now()
+
+	agg := newLastValueAgg(t, &desc, 10)
+	m, err := lastValue(&desc, labels, map[string]interface{}{"k": "v"}, agg, delta, now)
+	if err != nil {
+		t.Fatalf("lastValue: %v", err)
+	}
+	c, ok := m.(telemetry.Count)
+	if !ok {
+		t.Fatalf("expected a Count for a SumObserver, got %T", m)
+	}
+	if c.Value != 10 {
+		t.Fatalf("expected delta value 10 on first observation, got %v", c.Value)
+	}
+
+	agg2 := newLastValueAgg(t, &desc, 15)
+	m2, err := lastValue(&desc, labels, map[string]interface{}{"k": "v"}, agg2, delta, now.Add(time.Second))
+	if err != nil {
+		t.Fatalf("lastValue: %v", err)
+	}
+	c2 := m2.(telemetry.Count)
+	if c2.Value != 5 {
+		t.Fatalf("expected delta value 5 (15-10), got %v", c2.Value)
+	}
+}
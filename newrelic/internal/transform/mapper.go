@@ -0,0 +1,101 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+// AttributeMapper customizes the attributes attached to each Metric,
+// letting callers drop high-cardinality labels, remap OpenTelemetry
+// semantic-convention keys to the names New Relic expects, transform
+// values, and cap the total attribute count.
+type AttributeMapper interface {
+	// Map is applied to the attributes attributes() assembled from a
+	// Record's resource, labels, and descriptor, returning the attributes
+	// that should actually be attached to the Metric.
+	Map(attrs map[string]interface{}) map[string]interface{}
+}
+
+// AttributeMapperFunc adapts a function to an AttributeMapper.
+type AttributeMapperFunc func(map[string]interface{}) map[string]interface{}
+
+// Map calls f.
+func (f AttributeMapperFunc) Map(attrs map[string]interface{}) map[string]interface{} {
+	return f(attrs)
+}
+
+// defaultAttributeMapper returns attrs unchanged, preserving the exporter's
+// historical behavior of exporting every resource and metric label as-is.
+var defaultAttributeMapper AttributeMapper = AttributeMapperFunc(func(attrs map[string]interface{}) map[string]interface{} {
+	return attrs
+})
+
+// ConfigurableAttributeMapper is an AttributeMapper built from allow/deny
+// lists, key renames, a value transform, and a maximum attribute count. A
+// zero-value ConfigurableAttributeMapper passes every attribute through
+// unchanged.
+//
+// Deny is evaluated before Allow, and Rename before Transform. When
+// MaxAttributes is reached, which of the remaining user-supplied attributes
+// are kept is unspecified, since map iteration order is not defined. The New
+// Relic identification attributes attributes() adds (see
+// instrumentationProviderAttrKey and collectorNameAttrKey) are exempt from
+// Deny, Allow, and MaxAttributes, since dropping them would leave New Relic
+// unable to attribute the data to this exporter.
+type ConfigurableAttributeMapper struct {
+	// Allow, if non-empty, restricts output to only these keys.
+	Allow map[string]bool
+	// Deny drops these keys entirely, even if also present in Allow.
+	Deny map[string]bool
+	// Rename maps an input key to the output key New Relic expects, e.g.
+	// "service.instance.id" -> "instance.id".
+	Rename map[string]string
+	// Transform, if non-nil, is applied to every surviving attribute's
+	// value, keyed by its (possibly renamed) output key.
+	Transform func(key string, value interface{}) interface{}
+	// MaxAttributes caps the number of attributes returned. Zero means no
+	// cap.
+	MaxAttributes int
+}
+
+// reservedAttrKeys are the New Relic identification attributes attributes()
+// always adds. Map always keeps them, independent of Allow, Deny, and
+// MaxAttributes, so a user-configured mapper can never strip New Relic's
+// ability to attribute the data to this exporter.
+var reservedAttrKeys = map[string]bool{
+	instrumentationProviderAttrKey: true,
+	collectorNameAttrKey:           true,
+}
+
+// Map applies the allow/deny lists, renames, value transform, and
+// attribute cap configured on m.
+func (m ConfigurableAttributeMapper) Map(attrs map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(attrs))
+	kept := 0
+	for k, v := range attrs {
+		if reservedAttrKeys[k] {
+			continue
+		}
+		if m.Deny[k] {
+			continue
+		}
+		if len(m.Allow) > 0 && !m.Allow[k] {
+			continue
+		}
+		if name, ok := m.Rename[k]; ok {
+			k = name
+		}
+		if m.Transform != nil {
+			v = m.Transform(k, v)
+		}
+		if m.MaxAttributes > 0 && kept >= m.MaxAttributes {
+			continue
+		}
+		out[k] = v
+		kept++
+	}
+	for k := range reservedAttrKeys {
+		if v, ok := attrs[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}
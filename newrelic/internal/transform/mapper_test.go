@@ -0,0 +1,76 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import "testing"
+
+func TestConfigurableAttributeMapperDeny(t *testing.T) {
+	m := ConfigurableAttributeMapper{Deny: map[string]bool{"secret": true}}
+	out := m.Map(map[string]interface{}{"secret": "x", "keep": "y"})
+	if _, ok := out["secret"]; ok {
+		t.Fatalf("expected denied key to be dropped, got %v", out)
+	}
+	if out["keep"] != "y" {
+		t.Fatalf("expected non-denied key to survive, got %v", out)
+	}
+}
+
+func TestConfigurableAttributeMapperAllow(t *testing.T) {
+	m := ConfigurableAttributeMapper{Allow: map[string]bool{"keep": true}}
+	out := m.Map(map[string]interface{}{"keep": "y", "drop": "z"})
+	if len(out) != 1 || out["keep"] != "y" {
+		t.Fatalf("expected only the allowed key, got %v", out)
+	}
+}
+
+func TestConfigurableAttributeMapperRename(t *testing.T) {
+	m := ConfigurableAttributeMapper{Rename: map[string]string{"service.instance.id": "instance.id"}}
+	out := m.Map(map[string]interface{}{"service.instance.id": "abc"})
+	if out["instance.id"] != "abc" {
+		t.Fatalf("expected renamed key, got %v", out)
+	}
+	if _, ok := out["service.instance.id"]; ok {
+		t.Fatalf("expected original key to be gone, got %v", out)
+	}
+}
+
+func TestConfigurableAttributeMapperTransform(t *testing.T) {
+	m := ConfigurableAttributeMapper{
+		Transform: func(key string, value interface{}) interface{} {
+			return "transformed"
+		},
+	}
+	out := m.Map(map[string]interface{}{"a": "x"})
+	if out["a"] != "transformed" {
+		t.Fatalf("expected transformed value, got %v", out)
+	}
+}
+
+func TestConfigurableAttributeMapperMaxAttributes(t *testing.T) {
+	m := ConfigurableAttributeMapper{MaxAttributes: 1}
+	out := m.Map(map[string]interface{}{"a": 1, "b": 2})
+	if len(out) != 1 {
+		t.Fatalf("expected the cap to allow exactly one of two user attributes, got %v", out)
+	}
+}
+
+func TestConfigurableAttributeMapperKeepsReservedKeys(t *testing.T) {
+	m := ConfigurableAttributeMapper{
+		Deny:          map[string]bool{instrumentationProviderAttrKey: true},
+		MaxAttributes: 1,
+	}
+	attrs := map[string]interface{}{
+		"a":                            1,
+		"b":                            2,
+		instrumentationProviderAttrKey: instrumentationProviderAttrValue,
+		collectorNameAttrKey:           collectorNameAttrValue,
+	}
+	out := m.Map(attrs)
+	if out[instrumentationProviderAttrKey] != instrumentationProviderAttrValue {
+		t.Fatalf("expected %s to survive Deny, got %v", instrumentationProviderAttrKey, out)
+	}
+	if out[collectorNameAttrKey] != collectorNameAttrValue {
+		t.Fatalf("expected %s to survive MaxAttributes, got %v", collectorNameAttrKey, out)
+	}
+}
@@ -5,6 +5,7 @@ package transform
 
 import (
 	"errors"
+	"time"
 
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"go.opentelemetry.io/otel/api/label"
@@ -18,33 +19,89 @@ import (
 // aggregator is attempted.
 var ErrUnimplementedAgg = errors.New("unimplemented aggregator")
 
-// Record transforms an OpenTelemetry Record into a Metric.
+// Record transforms an OpenTelemetry Record into one or more Metrics.
+//
+// Most aggregations produce a single Metric, but some (such as Histogram and
+// Distribution) expand into several related Metrics, so callers must range
+// over the returned slice rather than assume a single value.
+//
+// LastValue aggregations from monotonic SumObserver instruments are routed
+// to a delta Count rather than a Gauge; see lastValue.
+//
+// The switch dispatches on Aggregation.Kind() rather than on the concrete
+// type so that new aggregation kinds can be added without changing this
+// function's structure. This SDK version has no dedicated kind for
+// Distribution aggregations (the array aggregator reports ExactKind), so
+// that case additionally type-asserts for aggregation.Distribution.
 //
 // An ErrUnimplementedAgg error is returned for unimplemented Aggregators.
-func Record(service string, res *resource.Resource, record metricsdk.Record) (telemetry.Metric, error) {
+func Record(service string, res *resource.Resource, record metricsdk.Record, opts ...Option) ([]telemetry.Metric, error) {
+	return recordWithBase(service, baseAttributes(res), record, newOptions(opts...), nil)
+}
+
+// recordWithBase is Record's implementation, parameterized on a
+// precomputed base (see baseAttributes), options, and an optional
+// per-worker scratch buffer (see attributes) so that Records can share all
+// of these across an entire batch instead of recomputing or reallocating
+// them per Record.
+func recordWithBase(service string, base map[string]interface{}, record metricsdk.Record, o options, buf map[string]interface{}) ([]telemetry.Metric, error) {
 	desc := record.Descriptor()
-	attrs := attributes(service, res, desc, record.Labels())
-	switch a := record.Aggregation().(type) {
-	case aggregation.MinMaxSumCount:
-		return minMaxSumCount(desc, attrs, a)
-	case aggregation.Sum:
-		return sum(desc, attrs, a)
+	labels := record.Labels()
+	attrs := attributes(base, service, desc, labels, o.mapper, buf)
+	now := record.EndTime()
+	agg := record.Aggregation()
+	switch agg.Kind() {
+	case aggregation.MinMaxSumCountKind:
+		a := agg.(aggregation.MinMaxSumCount)
+		m, err := minMaxSumCount(desc, attrs, a)
+		if err != nil {
+			return nil, err
+		}
+		return []telemetry.Metric{m}, nil
+	case aggregation.HistogramKind:
+		a := agg.(aggregation.Histogram)
+		return histogram(desc, labels, attrs, a, o.delta, now)
+	case aggregation.ExactKind:
+		// This SDK version has no DistributionKind: the exact/array
+		// aggregator reports ExactKind but also implements
+		// aggregation.Distribution, so type-assert for it directly.
+		if a, ok := agg.(aggregation.Distribution); ok {
+			return distribution(desc, attrs, a, o.quantiles)
+		}
+		return nil, ErrUnimplementedAgg
+	case aggregation.SumKind:
+		a := agg.(aggregation.Sum)
+		m, err := sum(desc, labels, attrs, a, o.delta, now)
+		if err != nil {
+			return nil, err
+		}
+		return []telemetry.Metric{m}, nil
+	case aggregation.LastValueKind:
+		a := agg.(aggregation.LastValue)
+		m, err := lastValue(desc, labels, attrs, a, o.delta, now)
+		if err != nil {
+			return nil, err
+		}
+		return []telemetry.Metric{m}, nil
 	}
 	return nil, ErrUnimplementedAgg
 }
 
-// TODO see also https://github.com/open-telemetry/opentelemetry-go/blob/4f3fab3ba7df677205e673ae743ee067c99dbe87/exporters/otlp/ for concurrent implementation
-// sum transforms a Sum Aggregator aggregation into a Count Metric.
-func sum(desc *metric.Descriptor, attrs map[string]interface{}, a aggregation.Sum) (telemetry.Metric, error) {
-	sum, err := a.Sum()
+// sum transforms a Sum Aggregator aggregation into a Count Metric. Sum is
+// cumulative, so delta converts it into the delta value and interval the
+// New Relic backend expects.
+func sum(desc *metric.Descriptor, labels *label.Set, attrs map[string]interface{}, a aggregation.Sum, delta *DeltaConverter, now time.Time) (telemetry.Metric, error) {
+	s, err := a.Sum()
 	if err != nil {
 		return nil, err
 	}
 
+	value, interval := delta.Convert(recordKey(desc, labels), s.CoerceToFloat64(desc.NumberKind()), now)
 	return telemetry.Count{
 		Name:       desc.Name(),
 		Attributes: attrs,
-		Value:      sum.CoerceToFloat64(desc.NumberKind()),
+		Value:      value,
+		Interval:   interval,
 	}, nil
 }
 
@@ -83,25 +140,72 @@ func minMaxSumCount(desc *metric.Descriptor, attrs map[string]interface{}, a agg
 	}, nil
 }
 
-func attributes(service string, res *resource.Resource, desc *metric.Descriptor, labels *label.Set) map[string]interface{} {
-	// By default include New Relic attributes and all labels
-	n := 2 + labels.Len() + res.Len()
-	if desc != nil {
-		if desc.Unit() != "" {
-			n++
+// recordKey builds a stable identifier for a descriptor/label-set pair so
+// that stateful transforms (e.g. cumulative-to-delta conversion) can track
+// values across collection cycles.
+func recordKey(desc *metric.Descriptor, labels *label.Set) string {
+	return desc.Name() + "/" + labels.Encoded(label.DefaultEncoder())
+}
+
+// baseAttributes returns the attributes shared by every Record collected
+// from res. Resource attributes are identical for every Record in a single
+// collection, so callers processing many Records (see Records) build this
+// once per collection and reuse it instead of re-iterating res.Iter() per
+// Record.
+func baseAttributes(res *resource.Resource) map[string]interface{} {
+	base := make(map[string]interface{}, res.Len())
+	for iter := res.Iter(); iter.Next(); {
+		kv := iter.Label()
+		base[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return base
+}
+
+// attributes layers a Record's labels and descriptor on top of base, with
+// the service name set beneath base as a fallback (a service.name from the
+// resource or the labels wins), then runs the result through mapper,
+// returning a new map; base itself is left untouched so it can be reused
+// for subsequent Records.
+//
+// If buf is non-nil, it is cleared and reused as scratch space for the
+// merge instead of allocating a fresh map, which lets Records amortize the
+// allocation (and the bucket growth that comes from not knowing n ahead of
+// time) across an entire worker's share of a batch. Since buf is reused by
+// the next Record, its contents are copied into a freshly sized, detached
+// map before being handed to mapper, so the value attributes returns is
+// always safe for the caller to keep.
+func attributes(base map[string]interface{}, service string, desc *metric.Descriptor, labels *label.Set, mapper AttributeMapper, buf map[string]interface{}) map[string]interface{} {
+	attrs := buf
+	if attrs != nil {
+		for k := range attrs {
+			delete(attrs, k)
 		}
-		if desc.Description() != "" {
+	} else {
+		// By default include New Relic attributes, the base resource
+		// attributes, and all labels.
+		n := 2 + labels.Len() + len(base)
+		if desc != nil {
+			if desc.Unit() != "" {
+				n++
+			}
+			if desc.Description() != "" {
+				n++
+			}
+		}
+		if service != "" {
 			n++
 		}
+		attrs = make(map[string]interface{}, n)
 	}
+
 	if service != "" {
-		n++
+		// This is intentionally overwritten by the resource and then the
+		// instrument itself if they contain the service name.
+		attrs[serviceNameAttrKey] = service
 	}
-	attrs := make(map[string]interface{}, n)
 
-	for iter := res.Iter(); iter.Next(); {
-		kv := iter.Label()
-		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	for k, v := range base {
+		attrs[k] = v
 	}
 
 	// If duplicate labels with Resource these take precedence.
@@ -118,13 +222,20 @@ func attributes(service string, res *resource.Resource, desc *metric.Descriptor,
 			attrs["description"] = desc.Description()
 		}
 	}
-	if service != "" {
-		attrs[serviceNameAttrKey] = service
-	}
 
 	// New Relic registered attributes to identify where this data came from.
 	attrs[instrumentationProviderAttrKey] = instrumentationProviderAttrValue
 	attrs[collectorNameAttrKey] = collectorNameAttrValue
 
-	return attrs
+	if buf != nil {
+		// Detach from the shared scratch buffer before it gets cleared and
+		// reused for the next Record.
+		out := make(map[string]interface{}, len(attrs))
+		for k, v := range attrs {
+			out[k] = v
+		}
+		attrs = out
+	}
+
+	return mapper.Map(attrs)
 }
@@ -0,0 +1,72 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import "runtime"
+
+// DefaultQuantiles are the quantiles reported for a Distribution aggregation
+// when no Option overrides them.
+var DefaultQuantiles = []float64{0.5, 0.9, 0.99}
+
+// options holds the configuration assembled from a set of Options.
+type options struct {
+	quantiles []float64
+	delta     *DeltaConverter
+	workers   int
+	mapper    AttributeMapper
+}
+
+// Option configures the behavior of Record.
+type Option func(*options)
+
+// WithQuantiles overrides the quantiles reported as gauges for Distribution
+// aggregations. The default is DefaultQuantiles.
+func WithQuantiles(quantiles ...float64) Option {
+	return func(o *options) {
+		o.quantiles = quantiles
+	}
+}
+
+// WithDeltaConverter overrides the DeltaConverter used to turn cumulative
+// aggregator values into deltas. Exporters that call Record on a recurring
+// collection loop should supply their own DeltaConverter and reuse it
+// across calls so that state carries over between cycles; otherwise Record
+// falls back to a package-level default.
+func WithDeltaConverter(delta *DeltaConverter) Option {
+	return func(o *options) {
+		o.delta = delta
+	}
+}
+
+// WithWorkers sets the number of goroutines Records uses to transform a
+// batch of Records concurrently. The default is runtime.GOMAXPROCS(0).
+func WithWorkers(workers int) Option {
+	return func(o *options) {
+		o.workers = workers
+	}
+}
+
+// WithAttributeMapper overrides how a Record's attributes are filtered and
+// remapped before being attached to its Metric(s). The default exports
+// every resource and metric label unchanged, matching this exporter's
+// historical behavior; see ConfigurableAttributeMapper for allow/deny
+// lists, key renaming, and attribute caps.
+func WithAttributeMapper(mapper AttributeMapper) Option {
+	return func(o *options) {
+		o.mapper = mapper
+	}
+}
+
+func newOptions(opts ...Option) options {
+	o := options{
+		quantiles: DefaultQuantiles,
+		delta:     defaultDeltaConverter,
+		workers:   runtime.GOMAXPROCS(0),
+		mapper:    defaultAttributeMapper,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"sync"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	metricsdk "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Records transforms a batch of Records concurrently, fanning the work out
+// across a bounded worker pool (see WithWorkers). This mirrors the
+// concurrent implementation of the upstream OTLP exporter, and additionally
+// computes the Resource's attributes once for the whole batch rather than
+// once per Record (see baseAttributes).
+//
+// Results are returned in the same order as records. Errors are collected
+// per-record rather than aborting the batch; errs[i] is non-nil exactly
+// when results[i] is nil.
+func Records(service string, res *resource.Resource, records []metricsdk.Record, opts ...Option) (results [][]telemetry.Metric, errs []error) {
+	o := newOptions(opts...)
+	base := baseAttributes(res)
+
+	results = make([][]telemetry.Metric, len(records))
+	errs = make([]error, len(records))
+
+	workers := o.workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(records) {
+		workers = len(records)
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			// buf is this worker's preallocated attribute scratch space,
+			// reused for the merge across every Record it's assigned, so
+			// only an exactly sized detached copy is allocated per Record
+			// (see attributes).
+			buf := make(map[string]interface{})
+			for i := range indices {
+				results[i], errs[i] = recordWithBase(service, base, records[i], o, buf)
+			}
+		}()
+	}
+	for i := range records {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results, errs
+}
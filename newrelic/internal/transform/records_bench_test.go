@@ -0,0 +1,67 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/label"
+	"go.opentelemetry.io/otel/api/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/export/metric"
+	sumaggregator "go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// benchRecords builds a checkpoint set of n Sum Records against res, each
+// with a distinct label so no two Records collapse to the same
+// DeltaConverter key.
+func benchRecords(n int, res *resource.Resource) []metricsdk.Record {
+	desc := metric.NewDescriptor("bench.counter", metric.CounterKind, metric.Int64NumberKind)
+	start := time.Now()
+	end := start.Add(time.Second)
+
+	records := make([]metricsdk.Record, n)
+	for i := 0; i < n; i++ {
+		agg := &sumaggregator.New(1)[0]
+		_ = agg.Update(context.Background(), metric.NewInt64Number(int64(i)), &desc)
+
+		labels := label.NewSet(kv.String("id", fmt.Sprintf("%d", i)))
+		records[i] = metricsdk.NewRecord(&desc, &labels, res, agg, start, end)
+	}
+	return records
+}
+
+// BenchmarkRecords demonstrates the throughput of the concurrent Records
+// fan-out over a checkpoint set comparable in size to a real collection
+// cycle (100k Records).
+func BenchmarkRecords(b *testing.B) {
+	res := resource.New(kv.String("service.name", "bench-service"))
+	records := benchRecords(100000, res)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Records("bench-service", res, records)
+	}
+}
+
+// BenchmarkRecordsSequential measures the same workload processed one
+// Record at a time, as a baseline for the concurrent fan-out above.
+func BenchmarkRecordsSequential(b *testing.B) {
+	res := resource.New(kv.String("service.name", "bench-service"))
+	records := benchRecords(100000, res)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		base := baseAttributes(res)
+		o := newOptions()
+		buf := make(map[string]interface{})
+		for _, r := range records {
+			_, _ = recordWithBase("bench-service", base, r, o, buf)
+		}
+	}
+}
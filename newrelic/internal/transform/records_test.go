@@ -0,0 +1,97 @@
+// Copyright 2019 New Relic Corporation. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/label"
+	"go.opentelemetry.io/otel/api/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/export/metric"
+	sumaggregator "go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// TestRecordsWorkerPool exercises the worker-pool fan-out with more Records
+// than workers, so a worker's scratch attribute buffer (see attributes) is
+// reused across several Records, and asserts every result is both correct
+// and independent of the others.
+func TestRecordsWorkerPool(t *testing.T) {
+	const n = 50
+	res := resource.New(kv.String("service.name", "worker-pool-test"))
+	start := time.Now()
+	end := start.Add(time.Second)
+
+	records := make([]metricsdk.Record, n)
+	for i := 0; i < n; i++ {
+		desc := metric.NewDescriptor(fmt.Sprintf("counter.%d", i), metric.CounterKind, metric.Int64NumberKind)
+		agg := &sumaggregator.New(1)[0]
+		if err := agg.Update(context.Background(), metric.NewInt64Number(int64(i)), &desc); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		labels := label.NewSet(kv.String("id", fmt.Sprintf("%d", i)))
+		records[i] = metricsdk.NewRecord(&desc, &labels, res, agg, start, end)
+	}
+
+	results, errs := Records("worker-pool-test", res, records, WithWorkers(4))
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("record %d: unexpected error: %v", i, err)
+		}
+	}
+
+	for i, metrics := range results {
+		if len(metrics) != 1 {
+			t.Fatalf("record %d: expected exactly one Metric, got %d", i, len(metrics))
+		}
+		c, ok := metrics[0].(telemetry.Count)
+		if !ok {
+			t.Fatalf("record %d: expected a Count, got %T", i, metrics[0])
+		}
+		if c.Value != float64(i) {
+			t.Fatalf("record %d: expected value %d, got %v (buffer reuse likely overwrote this result)", i, i, c.Value)
+		}
+		if id := c.Attributes["id"]; id != fmt.Sprintf("%d", i) {
+			t.Fatalf("record %d: expected id attribute %d, got %v (attrs aliased across a reused buffer?)", i, i, c.Attributes)
+		}
+	}
+}
+
+// TestAttributesResourceServiceNameWinsOverArgument asserts that a
+// service.name supplied via the Resource takes precedence over the service
+// string argument passed to Record/Records, since the latter is only a
+// fallback for when neither the Resource nor the instrument report one.
+func TestAttributesResourceServiceNameWinsOverArgument(t *testing.T) {
+	res := resource.New(kv.String("service.name", "resource-service"))
+	start := time.Now()
+	end := start.Add(time.Second)
+
+	desc := metric.NewDescriptor("counter", metric.CounterKind, metric.Int64NumberKind)
+	agg := &sumaggregator.New(1)[0]
+	if err := agg.Update(context.Background(), metric.NewInt64Number(1), &desc); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	labels := label.NewSet()
+	record := metricsdk.NewRecord(&desc, &labels, res, agg, start, end)
+
+	metrics, err := Record("argument-service", res, record)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if len(metrics) != 1 {
+		t.Fatalf("expected exactly one Metric, got %d", len(metrics))
+	}
+	c, ok := metrics[0].(telemetry.Count)
+	if !ok {
+		t.Fatalf("expected a Count, got %T", metrics[0])
+	}
+	if got := c.Attributes["service.name"]; got != "resource-service" {
+		t.Fatalf("expected the Resource's service.name to win over the service argument, got %v", got)
+	}
+}